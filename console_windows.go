@@ -0,0 +1,33 @@
+// +build windows
+
+package keyboard
+
+import "os"
+
+var (
+	out *os.File
+	in  *os.File
+)
+
+// initConsole opens the console handles that Size() and resizeWatcher in
+// resize_windows.go need, and starts resizeWatcher. It does not set up raw
+// mode or key decoding as the unix backend does.
+func initConsole() (err error) {
+	out, err = os.OpenFile("CONOUT$", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	in, err = os.OpenFile("CONIN$", os.O_RDONLY, 0)
+	if err != nil {
+		out.Close()
+		return err
+	}
+
+	go resizeWatcher()
+	return nil
+}
+
+func releaseConsole() {
+	in.Close()
+	out.Close()
+}
@@ -0,0 +1,361 @@
+package keyboard
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var ErrInterrupted = errors.New("keyboard: interrupted")
+
+// CompleterFunc returns completion candidates for the given line and cursor
+// position, where pos counts runes. prefixLen is the number of runes before
+// pos that the candidates replace.
+type CompleterFunc func(line string, pos int) (candidates []string, prefixLen int)
+
+// Editor is a VT100 line editor providing cursor motion, a kill-ring,
+// history navigation, reverse incremental search and tab completion on top
+// of the raw key events produced by this package.
+type Editor struct {
+	prompt   string
+	buf      []rune
+	pos      int
+	killed   []rune
+	history  []string
+	histPos  int
+	pending  string
+	mask     rune
+	hasMask  bool
+	complete CompleterFunc
+}
+
+// NewEditor creates an Editor that will display prompt before each line.
+func NewEditor(prompt string) *Editor {
+	return &Editor{prompt: prompt, histPos: -1}
+}
+
+// SetPasswordMode makes the editor echo mask instead of the typed runes.
+// Pass 0 to disable masking and go back to echoing real input.
+func (e *Editor) SetPasswordMode(mask rune) {
+	e.hasMask = mask != 0
+	e.mask = mask
+}
+
+// SetCompleter installs fn as the tab-completion callback. A nil fn disables
+// completion.
+func (e *Editor) SetCompleter(fn CompleterFunc) {
+	e.complete = fn
+}
+
+// AddHistory appends line to the end of the history ring.
+func (e *Editor) AddHistory(line string) {
+	e.history = append(e.history, line)
+}
+
+// SetHistory replaces the whole history ring with lines.
+func (e *Editor) SetHistory(lines []string) {
+	e.history = append([]string(nil), lines...)
+}
+
+// ReadLine reads a single line of input, applying editing keys until Enter
+// is pressed. It returns the entered text without the trailing newline.
+func (e *Editor) ReadLine() (string, error) {
+	e.buf = e.buf[:0]
+	e.pos = 0
+	e.histPos = -1
+	e.pending = ""
+
+	e.render()
+	for {
+		event, err := getKeyEvent()
+		if err != nil {
+			return "", err
+		}
+		r, key := event.rune, event.key
+
+		switch {
+		case key == KeyEnter || key == KeyCtrlM:
+			e.writeString("\r\n")
+			line := string(e.buf)
+			return line, nil
+		case key == KeyCtrlC:
+			return "", ErrInterrupted
+		case key == KeyArrowLeft || key == KeyCtrlB:
+			e.moveLeft()
+		case key == KeyArrowRight || key == KeyCtrlF:
+			e.moveRight()
+		case key == KeyHome || key == KeyCtrlA:
+			e.pos = 0
+		case key == KeyEnd || key == KeyCtrlE:
+			e.pos = len(e.buf)
+		case key == KeyAltB:
+			e.moveWordLeft()
+		case key == KeyAltF:
+			e.moveWordRight()
+		case key == KeyCtrlW:
+			e.killWordLeft()
+		case key == KeyCtrlK:
+			e.killToEnd()
+		case key == KeyCtrlY:
+			e.yank()
+		case key == KeyCtrlU:
+			e.killToStart()
+		case key == KeyCtrlR:
+			if err := e.reverseSearch(); err != nil {
+				return "", err
+			}
+		case key == KeyTab:
+			e.tabComplete()
+		case key == KeyArrowUp:
+			e.historyPrev()
+		case key == KeyArrowDown:
+			e.historyNext()
+		case key == KeyBackspace || key == KeyBackspace2:
+			e.backspace()
+		case key == KeyDelete || key == KeyCtrlD:
+			e.deleteRune()
+		case key == KeyPaste:
+			e.insertString(string(event.data))
+		case key == 0 && r != 0:
+			e.insert(r)
+		}
+
+		e.render()
+	}
+}
+
+func (e *Editor) insert(r rune) {
+	e.buf = append(e.buf, 0)
+	copy(e.buf[e.pos+1:], e.buf[e.pos:])
+	e.buf[e.pos] = r
+	e.pos++
+}
+
+// insertString inserts s at the cursor in one go, used for pasted text so
+// it doesn't get tokenized rune-by-rune like typed input.
+func (e *Editor) insertString(s string) {
+	if s == "" {
+		return
+	}
+	runes := []rune(s)
+	e.buf = append(e.buf[:e.pos], append(runes, e.buf[e.pos:]...)...)
+	e.pos += len(runes)
+}
+
+func (e *Editor) backspace() {
+	if e.pos == 0 {
+		return
+	}
+	e.buf = append(e.buf[:e.pos-1], e.buf[e.pos:]...)
+	e.pos--
+}
+
+func (e *Editor) deleteRune() {
+	if e.pos >= len(e.buf) {
+		return
+	}
+	e.buf = append(e.buf[:e.pos], e.buf[e.pos+1:]...)
+}
+
+func (e *Editor) moveLeft() {
+	if e.pos > 0 {
+		e.pos--
+	}
+}
+
+func (e *Editor) moveRight() {
+	if e.pos < len(e.buf) {
+		e.pos++
+	}
+}
+
+// moveWordLeft moves the cursor to the start of the previous word (Alt-B),
+// without touching the buffer.
+func (e *Editor) moveWordLeft() {
+	pos := e.pos
+	for pos > 0 && e.buf[pos-1] == ' ' {
+		pos--
+	}
+	for pos > 0 && e.buf[pos-1] != ' ' {
+		pos--
+	}
+	e.pos = pos
+}
+
+// moveWordRight moves the cursor to the end of the next word (Alt-F),
+// without touching the buffer.
+func (e *Editor) moveWordRight() {
+	pos := e.pos
+	for pos < len(e.buf) && e.buf[pos] == ' ' {
+		pos++
+	}
+	for pos < len(e.buf) && e.buf[pos] != ' ' {
+		pos++
+	}
+	e.pos = pos
+}
+
+// killWordLeft removes the word before the cursor (Ctrl-W) into the kill-ring.
+func (e *Editor) killWordLeft() {
+	start := e.pos
+	for start > 0 && e.buf[start-1] == ' ' {
+		start--
+	}
+	for start > 0 && e.buf[start-1] != ' ' {
+		start--
+	}
+	e.killed = append([]rune(nil), e.buf[start:e.pos]...)
+	e.buf = append(e.buf[:start], e.buf[e.pos:]...)
+	e.pos = start
+}
+
+// killToEnd removes everything from the cursor to the end of the line
+// (Ctrl-K) into the kill-ring.
+func (e *Editor) killToEnd() {
+	e.killed = append([]rune(nil), e.buf[e.pos:]...)
+	e.buf = e.buf[:e.pos]
+}
+
+// killToStart removes everything from the start of the line to the cursor
+// (Ctrl-U) into the kill-ring.
+func (e *Editor) killToStart() {
+	e.killed = append([]rune(nil), e.buf[:e.pos]...)
+	e.buf = append([]rune(nil), e.buf[e.pos:]...)
+	e.pos = 0
+}
+
+// yank re-inserts the last killed text at the cursor (Ctrl-Y).
+func (e *Editor) yank() {
+	if len(e.killed) == 0 {
+		return
+	}
+	e.buf = append(e.buf[:e.pos], append(append([]rune(nil), e.killed...), e.buf[e.pos:]...)...)
+	e.pos += len(e.killed)
+}
+
+func (e *Editor) historyPrev() {
+	if len(e.history) == 0 {
+		return
+	}
+	if e.histPos == -1 {
+		e.pending = string(e.buf)
+		e.histPos = len(e.history) - 1
+	} else if e.histPos > 0 {
+		e.histPos--
+	}
+	e.setLine(e.history[e.histPos])
+}
+
+func (e *Editor) historyNext() {
+	if e.histPos == -1 {
+		return
+	}
+	e.histPos++
+	if e.histPos >= len(e.history) {
+		e.histPos = -1
+		e.setLine(e.pending)
+		return
+	}
+	e.setLine(e.history[e.histPos])
+}
+
+func (e *Editor) setLine(s string) {
+	e.buf = []rune(s)
+	e.pos = len(e.buf)
+}
+
+func (e *Editor) tabComplete() {
+	if e.complete == nil {
+		return
+	}
+	candidates, prefixLen := e.complete(string(e.buf), e.pos)
+	if len(candidates) != 1 {
+		return
+	}
+	start := e.pos - prefixLen
+	if start < 0 {
+		return
+	}
+	rest := string(e.buf[e.pos:])
+	e.buf = []rune(string(e.buf[:start]) + candidates[0] + rest)
+	e.pos = start + len([]rune(candidates[0]))
+}
+
+// reverseSearch implements Ctrl-R incremental history search, terminating
+// on Enter (accept) or Ctrl-G/Esc (cancel).
+func (e *Editor) reverseSearch() error {
+	query := ""
+	match := ""
+	for {
+		e.writeString("\r\x1b[K(reverse-i-search)`" + query + "': " + match)
+		event, err := getKeyEvent()
+		if err != nil {
+			return err
+		}
+		r, key := event.rune, event.key
+		switch {
+		case key == KeyEnter || key == KeyCtrlM:
+			e.setLine(match)
+			return nil
+		case key == KeyEsc || key == KeyCtrlG:
+			return nil
+		case key == KeyBackspace || key == KeyBackspace2:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+			}
+		case key == 0 && r != 0:
+			query += string(r)
+		}
+		match = e.searchHistory(query)
+	}
+}
+
+func (e *Editor) searchHistory(query string) string {
+	if query == "" {
+		return ""
+	}
+	for i := len(e.history) - 1; i >= 0; i-- {
+		if strings.Contains(e.history[i], query) {
+			return e.history[i]
+		}
+	}
+	return ""
+}
+
+// render redraws the prompt and current buffer on the current line.
+func (e *Editor) render() {
+	e.writeString("\r\x1b[K" + e.prompt + e.display())
+	if back := len(e.buf) - e.pos; back > 0 {
+		e.writeString(cursorBack(back))
+	}
+}
+
+func (e *Editor) display() string {
+	if !e.hasMask {
+		return string(e.buf)
+	}
+	return strings.Repeat(string(e.mask), len(e.buf))
+}
+
+func (e *Editor) writeString(s string) {
+	out.WriteString(s)
+}
+
+func cursorBack(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return "\x1b[" + itoa(n) + "D"
+}
+
+func itoa(n int) string {
+	if n == 0 {
+		return "0"
+	}
+	digits := make([]byte, 0, 8)
+	for n > 0 {
+		digits = append([]byte{byte('0' + n%10)}, digits...)
+		n /= 10
+	}
+	return string(digits)
+}
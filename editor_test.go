@@ -0,0 +1,122 @@
+package keyboard
+
+import "testing"
+
+func newTestEditor(line string) *Editor {
+	e := NewEditor("> ")
+	e.buf = []rune(line)
+	e.pos = len(e.buf)
+	return e
+}
+
+func TestEditorKillWordLeftAndYank(t *testing.T) {
+	e := newTestEditor("hello cruel world")
+	e.pos = len("hello cruel ") // cursor right before "world"
+	e.killWordLeft()
+	if got := string(e.buf); got != "hello world" {
+		t.Fatalf("killWordLeft: got %q, want %q", got, "hello world")
+	}
+	if e.pos != len("hello ") {
+		t.Fatalf("killWordLeft: cursor at %d, want %d", e.pos, len("hello "))
+	}
+
+	e.yank()
+	if got := string(e.buf); got != "hello cruel world" {
+		t.Fatalf("yank: got %q, want %q", got, "hello cruel world")
+	}
+}
+
+func TestEditorKillToEndAndYank(t *testing.T) {
+	e := newTestEditor("remove this part")
+	e.pos = len("remove this")
+	e.killToEnd()
+	if got := string(e.buf); got != "remove this" {
+		t.Fatalf("killToEnd: got %q", got)
+	}
+	e.yank()
+	if got := string(e.buf); got != "remove this part" {
+		t.Fatalf("yank after killToEnd: got %q", got)
+	}
+}
+
+func TestEditorKillToStartAndYank(t *testing.T) {
+	e := newTestEditor("discard me keep me")
+	e.pos = len("discard me ")
+	e.killToStart()
+	if got := string(e.buf); got != "keep me" {
+		t.Fatalf("killToStart: got %q", got)
+	}
+	if e.pos != 0 {
+		t.Fatalf("killToStart: cursor at %d, want 0", e.pos)
+	}
+	e.yank()
+	if got := string(e.buf); got != "discard me keep me" {
+		t.Fatalf("yank after killToStart: got %q", got)
+	}
+}
+
+func TestEditorMoveWordLeftRight(t *testing.T) {
+	e := newTestEditor("one two three")
+	e.moveWordLeft()
+	if e.pos != len("one two ") {
+		t.Fatalf("moveWordLeft: cursor at %d, want %d", e.pos, len("one two "))
+	}
+	e.moveWordLeft()
+	if e.pos != len("one ") {
+		t.Fatalf("moveWordLeft twice: cursor at %d, want %d", e.pos, len("one "))
+	}
+	e.moveWordRight()
+	if e.pos != len("one two") {
+		t.Fatalf("moveWordRight: cursor at %d, want %d", e.pos, len("one two"))
+	}
+}
+
+func TestEditorInsertString(t *testing.T) {
+	e := newTestEditor("go ")
+	e.insertString("pasted text")
+	if got := string(e.buf); got != "go pasted text" {
+		t.Fatalf("insertString: got %q", got)
+	}
+	if e.pos != len("go pasted text") {
+		t.Fatalf("insertString: cursor at %d, want %d", e.pos, len("go pasted text"))
+	}
+}
+
+func TestEditorHistoryNavigation(t *testing.T) {
+	e := NewEditor("> ")
+	e.SetHistory([]string{"first", "second", "third"})
+	e.buf = []rune("typing")
+	e.pos = len(e.buf)
+
+	e.historyPrev()
+	if got := string(e.buf); got != "third" {
+		t.Fatalf("historyPrev: got %q, want %q", got, "third")
+	}
+	e.historyPrev()
+	if got := string(e.buf); got != "second" {
+		t.Fatalf("historyPrev again: got %q, want %q", got, "second")
+	}
+	e.historyNext()
+	if got := string(e.buf); got != "third" {
+		t.Fatalf("historyNext: got %q, want %q", got, "third")
+	}
+	e.historyNext()
+	if got := string(e.buf); got != "typing" {
+		t.Fatalf("historyNext past end: got %q, want pending line %q", got, "typing")
+	}
+}
+
+func TestEditorSearchHistory(t *testing.T) {
+	e := NewEditor("> ")
+	e.SetHistory([]string{"git status", "git commit -m fix", "ls -la"})
+
+	if got := e.searchHistory("commit"); got != "git commit -m fix" {
+		t.Fatalf("searchHistory(commit): got %q", got)
+	}
+	if got := e.searchHistory(""); got != "" {
+		t.Fatalf("searchHistory(\"\"): got %q, want empty", got)
+	}
+	if got := e.searchHistory("nope"); got != "" {
+		t.Fatalf("searchHistory(nope): got %q, want empty", got)
+	}
+}
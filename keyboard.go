@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"runtime"
 	"strings"
 	"syscall"
 	"unicode/utf8"
@@ -32,12 +31,37 @@ var (
 	// termbox inner state
 	orig_tios unix.Termios
 
-	sigio     = make(chan os.Signal, 1)
+	sigwinch  = make(chan os.Signal, 1)
 	quit      = make(chan int)
 	inbuf     = make([]byte, 0, 128)
 	input_buf = make(chan input_event)
+
+	// pollQuitR/pollQuitW are a self-pipe used to wake the poll(2) loop on
+	// shutdown, since a blocked Poll call can't be cancelled by a channel.
+	pollQuitR *os.File
+	pollQuitW *os.File
+
+	// pollDone is closed by pollInput right before it returns, so
+	// releaseConsole can wait for it to actually stop touching the tty fd
+	// before closing it out from under a still-running poll(2) call.
+	pollDone = make(chan struct{})
 )
 
+// winsize mirrors the kernel's struct winsize for TIOCGWINSZ.
+type winsize struct {
+	rows, cols, xpixel, ypixel uint16
+}
+
+// Size returns the current terminal dimensions in columns and rows.
+func Size() (cols, rows int, err error) {
+	var ws winsize
+	r, _, e := syscall.Syscall(unix.SYS_IOCTL, out.Fd(), uintptr(unix.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if r != 0 {
+		return 0, 0, os.NewSyscallError("SYS_IOCTL", e)
+	}
+	return int(ws.cols), int(ws.rows), nil
+}
+
 func fcntl(cmd int, arg int) error {
 	_, _, e := syscall.Syscall(unix.SYS_FCNTL, uintptr(in), uintptr(cmd), uintptr(arg))
 	if e != 0 {
@@ -74,13 +98,39 @@ func extract_event(inbuf []byte) (int, keyEvent) {
 	}
 
 	if inbuf[0] == '\033' {
+		// a bracketed paste frame takes priority over the regular escape
+		// sequence table, and may need more than one input_buf read before
+		// its end marker shows up
+		if size, event, ok := extractPaste(inbuf); ok {
+			return size, event
+		}
+
+		// mouse reports also live under ESC[ and must be checked before the
+		// generic escape-sequence table, which has no notion of them
+		if size, event, ok := extractMouse(inbuf); ok {
+			return size, event
+		}
+
 		// possible escape sequence
 		if size, event := parse_escape_sequence(inbuf); size != 0 {
 			return size, event
-		} else {
-			// it's not a recognized escape sequence, then return Esc
-			return len(inbuf), keyEvent{key: KeyEsc}
 		}
+
+		// Meta/Alt-letter sequences (ESC followed directly by a bare
+		// letter, no CSI '[' or SS3 'O') must be recognized before the
+		// catch-all Esc case below, or the letter gets swallowed along
+		// with the Esc as a single, unusable KeyEsc event
+		if len(inbuf) >= 2 {
+			switch inbuf[1] {
+			case 'b':
+				return 2, keyEvent{key: KeyAltB}
+			case 'f':
+				return 2, keyEvent{key: KeyAltF}
+			}
+		}
+
+		// it's not a recognized escape sequence, then return Esc
+		return len(inbuf), keyEvent{key: KeyEsc}
 	}
 
 	// if we're here, this is not an escape sequence and not an alt sequence
@@ -140,6 +190,9 @@ func inputEventsProducer() {
 }
 
 func initConsole() (err error) {
+	quit = make(chan int)
+	pollDone = make(chan struct{})
+
 	out, err = os.OpenFile("/dev/tty", unix.O_WRONLY, 0)
 	if err != nil {
 		return
@@ -154,14 +207,15 @@ func initConsole() (err error) {
 		return fmt.Errorf("Error while reading terminfo data: %v", err)
 	}
 
-	signal.Notify(sigio, unix.SIGIO)
+	signal.Notify(sigwinch, unix.SIGWINCH)
 
-	err = fcntl(unix.F_SETFL, unix.O_ASYNC|unix.O_NONBLOCK)
+	err = fcntl(unix.F_SETFL, unix.O_NONBLOCK)
 	if err != nil {
 		return
 	}
-	err = fcntl(unix.F_SETOWN, unix.Getpid())
-	if runtime.GOOS != "darwin" && err != nil {
+
+	pollQuitR, pollQuitW, err = os.Pipe()
+	if err != nil {
 		return
 	}
 
@@ -187,26 +241,12 @@ func initConsole() (err error) {
 	}
 
 	go func() {
-		buf := make([]byte, 128)
 		for {
 			select {
-			case <-sigio:
-				for {
-					bytesRead, err := syscall.Read(in, buf)
-					if err == unix.EAGAIN || err == unix.EWOULDBLOCK {
-						break
-					}
-					if err != nil {
-						bytesRead = 0
-					}
-					data := make([]byte, bytesRead)
-					copy(data, buf)
-					select {
-					case input_buf <- input_event{data, err}:
-						continue
-					case <-quit:
-						return
-					}
+			case <-sigwinch:
+				cols, rows, err := Size()
+				if err == nil {
+					produceEvent(keyEvent{key: KeyResize, width: cols, height: rows})
 				}
 			case <-quit:
 				return
@@ -214,13 +254,28 @@ func initConsole() (err error) {
 		}
 	}()
 
+	go pollInput()
+
 	go inputEventsProducer()
 	return
 }
 
 func releaseConsole() {
-	quit <- 1
+	if bracketedPasteEnabled {
+		SetBracketedPaste(false)
+	}
+	if mouseEnabled {
+		DisableMouse()
+	}
+	pollQuitW.Write([]byte{0})
+	// close, not send: inputEventsProducer, the sigwinch watcher and
+	// pollInput each select on quit independently, and a single value would
+	// only wake one
+	close(quit)
+	<-pollDone // wait for pollInput to stop touching in before we close it
 	ioctl(ioctl_SETATTR, &orig_tios)
 	out.Close()
 	unix.Close(in)
+	pollQuitR.Close()
+	pollQuitW.Close()
 }
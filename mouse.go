@@ -0,0 +1,174 @@
+package keyboard
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MouseMode selects which DECSET mouse-tracking protocol to enable.
+type MouseMode int
+
+const (
+	// MouseModeX10 reports button presses and releases, without motion
+	// (DECSET 1000).
+	MouseModeX10 MouseMode = iota
+	// MouseModeButton reports presses, releases and drag motion while a
+	// button is held (DECSET 1002).
+	MouseModeButton
+	// MouseModeAny reports all motion, button held or not (DECSET 1003).
+	MouseModeAny
+)
+
+// MouseButton identifies which button a MouseEvent refers to.
+type MouseButton int
+
+const (
+	MouseButtonLeft MouseButton = iota
+	MouseButtonMiddle
+	MouseButtonRight
+	MouseButtonNone
+	MouseWheelUp
+	MouseWheelDown
+)
+
+// MouseEvent describes a single mouse report decoded from the terminal.
+type MouseEvent struct {
+	Button  MouseButton
+	X, Y    int
+	Mod     Key
+	Motion  bool
+	Release bool
+}
+
+var mouseEnabled bool
+
+const (
+	mouseEnableX10    = "\033[?1000h"
+	mouseEnableButton = "\033[?1002h"
+	mouseEnableAny    = "\033[?1003h"
+	mouseEnableURxvt  = "\033[?1015h"
+	mouseEnableSGR    = "\033[?1006h"
+	mouseDisableAll   = "\033[?1000l\033[?1002l\033[?1003l\033[?1015l\033[?1006l"
+)
+
+// EnableMouse turns on mouse tracking, asking for both the SGR (1006) and
+// urxvt (1015) extended-coordinate encodings: a terminal understanding SGR
+// reports that way regardless, and urxvt-only terminals fall back to 1015
+// instead of the legacy X10 encoding, which overflows past column/row 223.
+func EnableMouse(mode MouseMode) {
+	mouseEnabled = true
+	switch mode {
+	case MouseModeButton:
+		out.WriteString(mouseEnableButton)
+	case MouseModeAny:
+		out.WriteString(mouseEnableAny)
+	default:
+		out.WriteString(mouseEnableX10)
+	}
+	out.WriteString(mouseEnableURxvt)
+	out.WriteString(mouseEnableSGR)
+}
+
+// DisableMouse turns off every mouse-tracking mode this package may have
+// enabled.
+func DisableMouse() {
+	mouseEnabled = false
+	out.WriteString(mouseDisableAll)
+}
+
+// extractMouse recognizes X10 (ESC [ M Cb Cx Cy), SGR 1006
+// (ESC [ < b ; x ; y M/m) and urxvt 1015 (ESC [ b ; x ; y M) mouse reports
+// at the start of inbuf.
+func extractMouse(inbuf []byte) (size int, event keyEvent, ok bool) {
+	if !mouseEnabled || len(inbuf) < 3 || inbuf[0] != '\033' || inbuf[1] != '[' {
+		return 0, keyEvent{}, false
+	}
+
+	if inbuf[2] == 'M' {
+		if len(inbuf) < 6 {
+			return 0, keyEvent{}, true // wait for the rest of the frame
+		}
+		cb := int(inbuf[3]) - 32
+		x := int(inbuf[4]) - 32 - 1
+		y := int(inbuf[5]) - 32 - 1
+		me := decodeButtonByte(cb, x, y)
+		return 6, keyEvent{key: KeyMouse, mouse: me}, true
+	}
+
+	if inbuf[2] == '<' {
+		end := indexAny(inbuf[3:], "Mm")
+		if end < 0 {
+			return 0, keyEvent{}, true // partial SGR frame, wait for more
+		}
+		fields := strings.SplitN(string(inbuf[3:3+end]), ";", 3)
+		if len(fields) != 3 {
+			return 3 + end + 1, keyEvent{}, true
+		}
+		cb, _ := strconv.Atoi(fields[0])
+		x, _ := strconv.Atoi(fields[1])
+		y, _ := strconv.Atoi(fields[2])
+		me := decodeButtonByte(cb, x-1, y-1)
+		me.Release = inbuf[3+end] == 'm'
+		return 3 + end + 1, keyEvent{key: KeyMouse, mouse: me}, true
+	}
+
+	if inbuf[2] >= '0' && inbuf[2] <= '9' {
+		// ESC [ digit also starts key sequences such as ESC[2~ or ESC[1;2A;
+		// only a CSI sequence whose final byte is 'M' is a urxvt report
+		end := -1
+		for i, b := range inbuf[2:] {
+			if b >= 0x40 && b <= 0x7e {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return 0, keyEvent{}, true // partial CSI sequence, wait for more
+		}
+		if inbuf[2+end] != 'M' {
+			return 0, keyEvent{}, false
+		}
+		fields := strings.SplitN(string(inbuf[2:2+end]), ";", 3)
+		if len(fields) != 3 {
+			return 2 + end + 1, keyEvent{}, true
+		}
+		cb, _ := strconv.Atoi(fields[0])
+		x, _ := strconv.Atoi(fields[1])
+		y, _ := strconv.Atoi(fields[2])
+		me := decodeButtonByte(cb-32, x-1, y-1)
+		return 2 + end + 1, keyEvent{key: KeyMouse, mouse: me}, true
+	}
+
+	return 0, keyEvent{}, false
+}
+
+func decodeButtonByte(cb, x, y int) MouseEvent {
+	me := MouseEvent{X: x, Y: y}
+	me.Motion = cb&32 != 0
+	switch cb & 0xc3 {
+	case 0:
+		me.Button = MouseButtonLeft
+	case 1:
+		me.Button = MouseButtonMiddle
+	case 2:
+		me.Button = MouseButtonRight
+	case 3:
+		me.Button = MouseButtonNone
+		me.Release = true
+	case 0x40:
+		me.Button = MouseWheelUp
+	case 0x41:
+		me.Button = MouseWheelDown
+	}
+	me.Mod = Key(cb & 0x1c)
+	return me
+}
+
+func indexAny(buf []byte, chars string) int {
+	for i, b := range buf {
+		if strings.IndexByte(chars, b) >= 0 {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,60 @@
+package keyboard
+
+import "testing"
+
+func TestExtractMouseX10(t *testing.T) {
+	mouseEnabled = true
+	defer func() { mouseEnabled = false }()
+
+	// left button press at column 5, row 10 (1-based on the wire)
+	frame := []byte{'\033', '[', 'M', byte(32 + 0), byte(32 + 5), byte(32 + 10)}
+	size, event, ok := extractMouse(frame)
+	if !ok || size != 6 {
+		t.Fatalf("extractMouse(X10): ok=%v size=%d, want ok=true size=6", ok, size)
+	}
+	if event.mouse.Button != MouseButtonLeft || event.mouse.X != 4 || event.mouse.Y != 9 {
+		t.Fatalf("extractMouse(X10): got %+v", event.mouse)
+	}
+}
+
+func TestExtractMouseSGR(t *testing.T) {
+	mouseEnabled = true
+	defer func() { mouseEnabled = false }()
+
+	frame := []byte("\033[<0;5;10M")
+	size, event, ok := extractMouse(frame)
+	if !ok || size != len(frame) {
+		t.Fatalf("extractMouse(SGR): ok=%v size=%d, want ok=true size=%d", ok, size, len(frame))
+	}
+	if event.mouse.Button != MouseButtonLeft || event.mouse.X != 4 || event.mouse.Y != 9 || event.mouse.Release {
+		t.Fatalf("extractMouse(SGR) press: got %+v", event.mouse)
+	}
+
+	release := []byte("\033[<0;5;10m")
+	_, event, ok = extractMouse(release)
+	if !ok || !event.mouse.Release {
+		t.Fatalf("extractMouse(SGR) release: got ok=%v mouse=%+v", ok, event.mouse)
+	}
+}
+
+func TestExtractMouseURxvt(t *testing.T) {
+	mouseEnabled = true
+	defer func() { mouseEnabled = false }()
+
+	frame := []byte("\033[32;5;10M")
+	size, event, ok := extractMouse(frame)
+	if !ok || size != len(frame) {
+		t.Fatalf("extractMouse(urxvt): ok=%v size=%d, want ok=true size=%d", ok, size, len(frame))
+	}
+	if event.mouse.Button != MouseButtonLeft || event.mouse.X != 4 || event.mouse.Y != 9 {
+		t.Fatalf("extractMouse(urxvt): got %+v", event.mouse)
+	}
+}
+
+func TestExtractMouseDisabled(t *testing.T) {
+	mouseEnabled = false
+	frame := []byte("\033[<0;5;10M")
+	if _, _, ok := extractMouse(frame); ok {
+		t.Fatal("extractMouse: expected ok=false when mouse tracking is not enabled")
+	}
+}
@@ -0,0 +1,57 @@
+package keyboard
+
+const (
+	bracketedPasteStart = "\033[200~"
+	bracketedPasteEnd   = "\033[201~"
+	enableBracketedCSI  = "\033[?2004h"
+	disableBracketedCSI = "\033[?2004l"
+)
+
+var bracketedPasteEnabled bool
+
+// SetBracketedPaste enables or disables xterm bracketed-paste mode. While
+// enabled, text pasted into the terminal arrives as a single keyEvent with
+// Key == KeyPaste instead of being tokenized rune-by-rune.
+func SetBracketedPaste(enable bool) {
+	bracketedPasteEnabled = enable
+	if enable {
+		out.WriteString(enableBracketedCSI)
+	} else {
+		out.WriteString(disableBracketedCSI)
+	}
+}
+
+// extractPaste recognizes a complete ESC[200~ ... ESC[201~ frame at the
+// start of inbuf. It returns size == 0 if the start marker is present but
+// the end marker hasn't arrived yet, so callers wait for more input_buf
+// reads before consuming anything.
+func extractPaste(inbuf []byte) (size int, event keyEvent, ok bool) {
+	if !bracketedPasteEnabled {
+		return 0, keyEvent{}, false
+	}
+	if len(inbuf) < len(bracketedPasteStart) || string(inbuf[:len(bracketedPasteStart)]) != bracketedPasteStart {
+		return 0, keyEvent{}, false
+	}
+
+	body := inbuf[len(bracketedPasteStart):]
+	idx := indexOf(body, bracketedPasteEnd)
+	if idx < 0 {
+		// start marker seen, end marker not yet buffered: wait for more data
+		return 0, keyEvent{}, true
+	}
+
+	payload := make([]byte, idx)
+	copy(payload, body[:idx])
+	size = len(bracketedPasteStart) + idx + len(bracketedPasteEnd)
+	return size, keyEvent{key: KeyPaste, data: payload}, true
+}
+
+func indexOf(haystack []byte, needle string) int {
+	n := len(needle)
+	for i := 0; i+n <= len(haystack); i++ {
+		if string(haystack[i:i+n]) == needle {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,47 @@
+package keyboard
+
+import "testing"
+
+func TestExtractPasteCompleteFrame(t *testing.T) {
+	bracketedPasteEnabled = true
+	defer func() { bracketedPasteEnabled = false }()
+
+	frame := []byte(bracketedPasteStart + "hello\nworld" + bracketedPasteEnd + "trailing")
+	size, event, ok := extractPaste(frame)
+	if !ok {
+		t.Fatal("extractPaste: expected ok=true for a bracketed frame")
+	}
+	if string(event.data) != "hello\nworld" {
+		t.Fatalf("extractPaste: payload = %q, want %q", event.data, "hello\nworld")
+	}
+	if event.key != KeyPaste {
+		t.Fatalf("extractPaste: key = %v, want KeyPaste", event.key)
+	}
+	if size != len(frame)-len("trailing") {
+		t.Fatalf("extractPaste: size = %d, want to stop right after the end marker", size)
+	}
+}
+
+func TestExtractPastePartialFrame(t *testing.T) {
+	bracketedPasteEnabled = true
+	defer func() { bracketedPasteEnabled = false }()
+
+	// the end marker hasn't arrived yet, as if split across input_buf reads
+	frame := []byte(bracketedPasteStart + "still typ")
+	size, _, ok := extractPaste(frame)
+	if !ok {
+		t.Fatal("extractPaste: expected ok=true (recognized start marker) while waiting for the rest")
+	}
+	if size != 0 {
+		t.Fatalf("extractPaste: size = %d, want 0 while the frame is incomplete", size)
+	}
+}
+
+func TestExtractPasteDisabled(t *testing.T) {
+	bracketedPasteEnabled = false
+	frame := []byte(bracketedPasteStart + "x" + bracketedPasteEnd)
+	_, _, ok := extractPaste(frame)
+	if ok {
+		t.Fatal("extractPaste: expected ok=false when bracketed paste is not enabled")
+	}
+}
@@ -0,0 +1,62 @@
+// +build !windows
+
+package keyboard
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// pollInput replaces the old SIGIO+O_ASYNC scheme: it blocks in poll(2) on
+// the tty fd and the pollQuit self-pipe, so shutdown is deterministic and
+// doesn't depend on a signal that other libraries in the process might also
+// be registering for.
+func pollInput() {
+	defer close(pollDone)
+
+	buf := make([]byte, 128)
+	fds := []unix.PollFd{
+		{Fd: int32(in), Events: unix.POLLIN},
+		{Fd: int32(pollQuitR.Fd()), Events: unix.POLLIN},
+	}
+
+	for {
+		_, err := unix.Poll(fds, -1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			select {
+			case input_buf <- input_event{nil, err}:
+			case <-quit:
+			}
+			return
+		}
+
+		if fds[1].Revents&unix.POLLIN != 0 {
+			return
+		}
+
+		if fds[0].Revents&unix.POLLIN == 0 {
+			continue
+		}
+
+		for {
+			bytesRead, err := syscall.Read(in, buf)
+			if err == unix.EAGAIN || err == unix.EWOULDBLOCK {
+				break
+			}
+			data := make([]byte, bytesRead)
+			copy(data, buf)
+			select {
+			case input_buf <- input_event{data, err}:
+			case <-quit:
+				return
+			}
+			if err != nil || bytesRead == 0 {
+				break
+			}
+		}
+	}
+}
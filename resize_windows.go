@@ -0,0 +1,71 @@
+// +build windows
+
+package keyboard
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+	procReadConsoleInputW          = kernel32.NewProc("ReadConsoleInputW")
+)
+
+type coord struct {
+	x, y int16
+}
+
+type smallRect struct {
+	left, top, right, bottom int16
+}
+
+type consoleScreenBufferInfo struct {
+	size              coord
+	cursorPosition    coord
+	attributes        uint16
+	window            smallRect
+	maximumWindowSize coord
+}
+
+const windowBufferSizeEvent = 0x0004
+
+// inputRecord mirrors INPUT_RECORD. We only read the EventType and, for a
+// WINDOW_BUFFER_SIZE_EVENT, the dwSize COORD that starts its Event union;
+// the padding absorbs the rest of the union so the struct is the right
+// size for ReadConsoleInputW to write into.
+type inputRecord struct {
+	eventType uint16
+	_         uint16
+	size      coord
+	_         [12]byte
+}
+
+// Size returns the current console window dimensions in columns and rows.
+func Size() (cols, rows int, err error) {
+	var info consoleScreenBufferInfo
+	r, _, e := procGetConsoleScreenBufferInfo.Call(out.Fd(), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return 0, 0, e
+	}
+	return int(info.window.right-info.window.left) + 1, int(info.window.bottom-info.window.top) + 1, nil
+}
+
+// resizeWatcher blocks reading console input records on in and reports
+// WINDOW_BUFFER_SIZE_EVENT records through produceEvent, since Windows
+// consoles have no SIGWINCH equivalent. It returns once ReadConsoleInputW
+// fails, which happens once releaseConsole closes the input handle.
+func resizeWatcher() {
+	var rec inputRecord
+	var read uint32
+	for {
+		r, _, _ := procReadConsoleInputW.Call(in.Fd(), uintptr(unsafe.Pointer(&rec)), 1, uintptr(unsafe.Pointer(&read)))
+		if r == 0 {
+			return
+		}
+		if rec.eventType == windowBufferSizeEvent {
+			produceEvent(keyEvent{key: KeyResize, width: int(rec.size.x), height: int(rec.size.y)})
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package keyboard
+
+import (
+	"os"
+	"testing"
+)
+
+// Size() needs a real tty to return meaningful dimensions, which isn't
+// available in a unit test; this instead checks that pointing it at a
+// non-tty fd surfaces the ioctl failure instead of panicking or silently
+// returning a bogus size.
+func TestSizeOnNonTTYReturnsError(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "keyboard-size-test")
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	defer f.Close()
+
+	prevOut := out
+	out = f
+	defer func() { out = prevOut }()
+
+	cols, rows, err := Size()
+	if err == nil {
+		t.Fatal("Size: expected an error for a non-tty fd, got nil")
+	}
+	if cols != 0 || rows != 0 {
+		t.Fatalf("Size: got (%d, %d) alongside an error, want (0, 0)", cols, rows)
+	}
+}
@@ -0,0 +1,216 @@
+package keyboard
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// capNames lists the capability names this loader resolves into keys. A
+// name's index is its slot in the keys table (and in xtermKeys), not its
+// position in the terminfo string table. Only a subset of the full table is
+// needed.
+var capNames = []string{
+	"kcuu1", "kcud1", "kcub1", "kcuf1", "khome", "kend",
+	"kich1", "kdch1", "kpp", "knp",
+	"kf1", "kf2", "kf3", "kf4", "kf5", "kf6", "kf7", "kf8", "kf9", "kf10", "kf11", "kf12",
+	// shifted variants of the arrows, as produced by ncurses' xterm+ entries.
+	// kUP and kDN are extended capabilities, which readTerminfoStrings does
+	// not parse, so a terminfo-sourced table leaves their slots empty; only
+	// the xterm fallback table fills them.
+	"kUP", "kDN", "kLFT", "kRIT",
+}
+
+// xtermKeys is the hardcoded escape-sequence table this package shipped
+// before terminfo support existed. It stays in the same order as capNames
+// so it can stand in for a terminfo-sourced table unchanged, and is used
+// whenever $TERM has no usable compiled terminfo entry.
+var xtermKeys = []string{
+	"\033[A", "\033[B", "\033[D", "\033[C", "\033OH", "\033OF",
+	"\033[2~", "\033[3~", "\033[5~", "\033[6~",
+	"\033OP", "\033OQ", "\033OR", "\033OS", "\033[15~", "\033[17~", "\033[18~", "\033[19~", "\033[20~", "\033[21~", "\033[23~", "\033[24~",
+	"\033[1;2A", "\033[1;2B", "\033[1;2D", "\033[1;2C",
+}
+
+// setup_term populates keys, preferring the escape sequences the compiled
+// terminfo entry for $TERM advertises and falling back to the hardcoded
+// xterm table only when no terminfo entry can be found or parsed.
+func setup_term() error {
+	if table, err := loadTerminfoKeys(); err == nil {
+		keys = table
+		return nil
+	}
+	keys = xtermKeys
+	return nil
+}
+
+// loadTerminfoKeys builds the same escape-sequence -> Key table that the
+// hardcoded xterm table provides, but sourced from the compiled terminfo
+// entry for $TERM. It returns an error if no terminfo file can be found or
+// parsed, in which case callers should fall back to the builtin table.
+func loadTerminfoKeys() ([]string, error) {
+	term := os.Getenv("TERM")
+	if term == "" {
+		return nil, fmt.Errorf("keyboard: TERM is not set")
+	}
+
+	path, err := findTerminfoFile(term)
+	if err != nil {
+		return nil, err
+	}
+
+	strs, err := readTerminfoStrings(path)
+	if err != nil {
+		return nil, err
+	}
+
+	table := make([]string, len(capNames))
+	found := 0
+	for i, name := range capNames {
+		if seq, ok := strs[name]; ok && seq != "" {
+			table[i] = seq
+			found++
+		}
+	}
+	if found == 0 {
+		return nil, fmt.Errorf("keyboard: no usable key capabilities in terminfo entry %q", term)
+	}
+	return table, nil
+}
+
+// findTerminfoFile searches the standard terminfo directories for term,
+// honoring $TERMINFO, $HOME/.terminfo, $TERMINFO_DIRS and the system
+// directories, in that order.
+func findTerminfoFile(term string) (string, error) {
+	if term == "" {
+		return "", fmt.Errorf("keyboard: empty TERM")
+	}
+	firstChar := term[0:1]
+	hexChar := fmt.Sprintf("%x", term[0])
+
+	var dirs []string
+	if ti := os.Getenv("TERMINFO"); ti != "" {
+		dirs = append(dirs, ti)
+	}
+	if home := os.Getenv("HOME"); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	if tdirs := os.Getenv("TERMINFO_DIRS"); tdirs != "" {
+		dirs = append(dirs, strings.Split(tdirs, ":")...)
+	}
+	dirs = append(dirs, "/etc/terminfo", "/lib/terminfo", "/usr/share/terminfo")
+
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		for _, sub := range []string{firstChar, hexChar} {
+			path := filepath.Join(dir, sub, term)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("keyboard: no terminfo entry found for %q", term)
+}
+
+// readTerminfoStrings parses the legacy (non-extended) terminfo binary
+// format enough to recover the string capability table, keyed by name.
+func readTerminfoStrings(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var header [6]int16
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	const magic = 0432
+	if int(header[0]) != magic {
+		return nil, fmt.Errorf("keyboard: %q is not a legacy terminfo file", path)
+	}
+
+	namesLen, boolLen, numCount, strCount, strTableLen := int(header[1]), int(header[2]), int(header[3]), int(header[4]), int(header[5])
+
+	if _, err := r.Discard(namesLen); err != nil {
+		return nil, err
+	}
+	if _, err := r.Discard(boolLen); err != nil {
+		return nil, err
+	}
+	if (namesLen+boolLen)%2 != 0 {
+		if _, err := r.Discard(1); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := r.Discard(numCount * 2); err != nil {
+		return nil, err
+	}
+
+	offsets := make([]int16, strCount)
+	if err := binary.Read(r, binary.LittleEndian, &offsets); err != nil {
+		return nil, err
+	}
+
+	strTable := make([]byte, strTableLen)
+	if _, err := io.ReadFull(r, strTable); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string)
+	for i, off := range offsets {
+		if i >= len(terminfoStrNames) || off < 0 {
+			continue
+		}
+		end := int(off)
+		for end < len(strTable) && strTable[end] != 0 {
+			end++
+		}
+		result[terminfoStrNames[i]] = string(strTable[off:end])
+	}
+	return result, nil
+}
+
+// terminfoStrNames is the standard terminfo string-capability order (a
+// prefix of the System V string table; only the names we care about need
+// accurate positions, the rest are kept so offsets line up).
+var terminfoStrNames = []string{
+	"cbt", "bel", "cr", "csr", "tbc", "clear", "el", "ed", "hpa", "cmdch",
+	"cup", "cud1", "home", "civis", "cub1", "mrcup", "cnorm", "cuf1", "ll",
+	"cuu1", "cvvis", "dch1", "dl1", "dsl", "hd", "smacs", "blink", "bold",
+	"smcup", "smdc", "dim", "smir", "invis", "prot", "rev", "smso", "smul",
+	"ech", "rmacs", "sgr0", "rmcup", "rmdc", "rmir", "rmso", "rmul", "flash",
+	"ff", "fsl", "is1", "is2", "is3", "if", "ich1", "il1", "ip", "kbs",
+	"ktbc", "kclr", "kctab", "kdch1", "kdl1", "kcud1", "krmir", "kel",
+	"ked", "kf0", "kf1", "kf10", "kf2", "kf3", "kf4", "kf5", "kf6", "kf7",
+	"kf8", "kf9", "khome", "kich1", "kil1", "kcub1", "kll", "knp", "kpp",
+	"kcuf1", "kind", "kri", "khts", "kcuu1", "rmkx", "smkx", "lf0", "lf1",
+	"lf10", "lf2", "lf3", "lf4", "lf5", "lf6", "lf7", "lf8", "lf9",
+	"rmm", "smm", "nel", "pad", "dch", "dl", "cud", "ich",
+	"indn", "il", "cub", "cuf", "rin", "cuu", "pfkey", "pfloc", "pfx",
+	"mc0", "mc4", "mc5", "rep", "rs1", "rs2", "rs3", "rf", "rc", "vpa",
+	"sc", "ind", "ri", "sgr", "hts", "wind", "ht", "tsl", "uc", "hu",
+	"iprog", "ka1", "ka3", "kb2", "kc1", "kc3", "mc5p", "rmp", "acsc",
+	"pln", "kcbt", "smxon", "rmxon", "smam", "rmam", "xonc", "xoffc",
+	"enacs", "smln", "rmln", "kbeg", "kcan", "kclo", "kcmd", "kcpy",
+	"kcrt", "kend", "kent", "kext", "kfnd", "khlp", "kmrk", "kmsg",
+	"kmov", "knxt", "kopn", "kopt", "kprv", "kprt", "krdo", "kref",
+	"krfr", "krpl", "krst", "kres", "ksav", "kspd", "kund", "kBEG",
+	"kCAN", "kCMD", "kCPY", "kCRT", "kDC", "kDL", "kslt", "kEND", "kEOL",
+	"kEXT", "kFND", "kHLP", "kHOM", "kIC", "kLFT", "kMSG", "kMOV", "kNXT",
+	"kOPT", "kPRV", "kPRT", "kRDO", "kRPL", "kRIT", "kRES", "kSAV",
+	"kSPD", "kUND", "rfi", "kf11", "kf12", "kf13", "kf14", "kf15", "kf16",
+	"kf17", "kf18", "kf19", "kf20", "kf21", "kf22", "kf23", "kf24",
+	"kf25", "kf26", "kf27", "kf28", "kf29", "kf30", "kf31", "kf32",
+	"kf33", "kf34", "kf35", "kf36", "kf37", "kf38", "kf39", "kf40",
+	"kf41", "kf42", "kf43", "kf44", "kf45", "kf46", "kf47", "kf48",
+	"kf49", "kf50", "kf51", "kf52", "kf53", "kf54", "kf55", "kf56",
+	"kf57", "kf58", "kf59", "kf60", "kf61", "kf62", "kf63",
+}
@@ -0,0 +1,96 @@
+package keyboard
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildTerminfoFile writes a minimal legacy (non-extended) terminfo binary
+// exposing a single string capability, named capName with value capValue,
+// at the path findTerminfoFile would look it up under.
+func buildTerminfoFile(t *testing.T, dir, term, capName, capValue string) {
+	t.Helper()
+
+	strIndex := -1
+	for i, name := range terminfoStrNames {
+		if name == capName {
+			strIndex = i
+			break
+		}
+	}
+	if strIndex < 0 {
+		t.Fatalf("capability %q not present in terminfoStrNames", capName)
+	}
+
+	name := []byte(term + "\x00")
+	if len(name)%2 != 0 {
+		t.Fatalf("test setup: term name length must keep the names section even, got %q", term)
+	}
+
+	strTable := append([]byte(capValue), 0)
+	offsets := make([]int16, strIndex+1)
+	for i := range offsets {
+		offsets[i] = -1
+	}
+	offsets[strIndex] = 0
+
+	var buf bytes.Buffer
+	header := [6]int16{0432, int16(len(name)), 0, 0, int16(len(offsets)), int16(len(strTable))}
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		t.Fatalf("write header: %v", err)
+	}
+	buf.Write(name)
+	if err := binary.Write(&buf, binary.LittleEndian, offsets); err != nil {
+		t.Fatalf("write offsets: %v", err)
+	}
+	buf.Write(strTable)
+
+	subdir := filepath.Join(dir, term[0:1])
+	if err := os.MkdirAll(subdir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(subdir, term), buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("write terminfo file: %v", err)
+	}
+}
+
+func TestLoadTerminfoKeysFromCompiledEntry(t *testing.T) {
+	dir := t.TempDir()
+	const term = "xtermtest"
+	buildTerminfoFile(t, dir, term, "kcuu1", "\033[A")
+
+	t.Setenv("TERMINFO", dir)
+	t.Setenv("TERM", term)
+
+	table, err := loadTerminfoKeys()
+	if err != nil {
+		t.Fatalf("loadTerminfoKeys: %v", err)
+	}
+	if table[0] != "\033[A" {
+		t.Fatalf("loadTerminfoKeys: kcuu1 slot = %q, want %q", table[0], "\033[A")
+	}
+}
+
+func TestLoadTerminfoKeysMissingTerm(t *testing.T) {
+	t.Setenv("TERMINFO", t.TempDir())
+	t.Setenv("TERM", "no-such-term")
+
+	if _, err := loadTerminfoKeys(); err == nil {
+		t.Fatal("loadTerminfoKeys: expected an error for a term with no terminfo entry")
+	}
+}
+
+func TestSetupTermFallsBackToXtermTable(t *testing.T) {
+	t.Setenv("TERMINFO", t.TempDir())
+	t.Setenv("TERM", "no-such-term")
+
+	if err := setup_term(); err != nil {
+		t.Fatalf("setup_term: %v", err)
+	}
+	if len(keys) == 0 || keys[0] != xtermKeys[0] {
+		t.Fatalf("setup_term: expected the builtin xterm table as a fallback, got %v", keys)
+	}
+}
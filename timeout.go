@@ -0,0 +1,45 @@
+package keyboard
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrTimedOut is returned by GetKeyWithTimeout when no key arrives within d.
+var ErrTimedOut = errors.New("keyboard: timed out waiting for a key")
+
+// getKeyEvent blocks for the next raw keyEvent. Unlike GetKey, it keeps
+// fields (such as a bracketed-paste payload or a decoded mouse report) that
+// the simpler (rune, Key, error) API can't express, for consumers in this
+// package that need them.
+func getKeyEvent() (keyEvent, error) {
+	event := <-input_comm
+	return event, event.err
+}
+
+// GetKeyWithTimeout behaves like GetKey but gives up after d if no event has
+// arrived, returning ErrTimedOut.
+func GetKeyWithTimeout(d time.Duration) (rune, Key, error) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case event := <-input_comm:
+		return event.rune, event.key, event.err
+	case <-timer.C:
+		return 0, 0, ErrTimedOut
+	}
+}
+
+// GetKeyContext behaves like GetKey but returns ctx.Err() as soon as ctx is
+// done, letting callers integrate key reads with the rest of a Go program's
+// context tree.
+func GetKeyContext(ctx context.Context) (rune, Key, error) {
+	select {
+	case event := <-input_comm:
+		return event.rune, event.key, event.err
+	case <-ctx.Done():
+		return 0, 0, ctx.Err()
+	}
+}
@@ -0,0 +1,60 @@
+package keyboard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetKeyWithTimeoutReceivesEvent(t *testing.T) {
+	input_comm = make(chan keyEvent, 1)
+	defer close(input_comm)
+
+	input_comm <- keyEvent{rune: 'x', key: 0}
+
+	r, key, err := GetKeyWithTimeout(time.Second)
+	if err != nil {
+		t.Fatalf("GetKeyWithTimeout: unexpected error %v", err)
+	}
+	if r != 'x' || key != 0 {
+		t.Fatalf("GetKeyWithTimeout: got (%q, %v), want ('x', 0)", r, key)
+	}
+}
+
+func TestGetKeyWithTimeoutExpires(t *testing.T) {
+	input_comm = make(chan keyEvent)
+	defer close(input_comm)
+
+	_, _, err := GetKeyWithTimeout(20 * time.Millisecond)
+	if err != ErrTimedOut {
+		t.Fatalf("GetKeyWithTimeout: err = %v, want ErrTimedOut", err)
+	}
+}
+
+func TestGetKeyContextReceivesEvent(t *testing.T) {
+	input_comm = make(chan keyEvent, 1)
+	defer close(input_comm)
+
+	input_comm <- keyEvent{key: KeyEnter}
+
+	r, key, err := GetKeyContext(context.Background())
+	if err != nil {
+		t.Fatalf("GetKeyContext: unexpected error %v", err)
+	}
+	if r != 0 || key != KeyEnter {
+		t.Fatalf("GetKeyContext: got (%q, %v), want (0, KeyEnter)", r, key)
+	}
+}
+
+func TestGetKeyContextCancelled(t *testing.T) {
+	input_comm = make(chan keyEvent)
+	defer close(input_comm)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := GetKeyContext(ctx)
+	if err != context.Canceled {
+		t.Fatalf("GetKeyContext: err = %v, want context.Canceled", err)
+	}
+}